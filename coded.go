@@ -0,0 +1,142 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// CodedError is a DefaultError that also carries a stable, registered
+// (namespace, code) pair, for mapping an internal error chain onto a wire
+// protocol response (gRPC status, HTTP status, ABCI response) without
+// losing the human-readable chain. Create one with Register.
+type CodedError struct {
+	DefaultError
+	namespace string
+	code      uint32
+}
+
+// Code returns the registered code of this error.
+func (e CodedError) Code() uint32 {
+	return e.code
+}
+
+// Namespace returns the registered namespace of this error.
+func (e CodedError) Namespace() string {
+	return e.namespace
+}
+
+// Wrap the passed error in this error and return a copy, preserving the
+// namespace and code.
+func (e CodedError) Wrap(err error) Error {
+	e.DefaultError = e.DefaultError.Wrap(err).(DefaultError)
+	return e
+}
+
+// WithValue attaches a key/value pair to the error and returns a copy,
+// preserving the namespace and code.
+func (e CodedError) WithValue(key interface{}, value interface{}) Error {
+	e.DefaultError = e.DefaultError.WithValue(key, value).(DefaultError)
+	return e
+}
+
+// withStack returns a copy of e with its stack trace replaced by s,
+// preserving the namespace and code. This is what lets Append/Wrap/
+// Appends/Appendf re-stack a CodedError without losing its type, since they
+// check for the stackSetter interface rather than asserting DefaultError.
+func (e CodedError) withStack(s *stack) Error {
+	e.DefaultError = e.DefaultError.withStack(s).(DefaultError)
+	return e
+}
+
+// Coder is implemented by errors that carry a registered namespace and
+// code, such as CodedError.
+type Coder interface {
+	Code() uint32
+	Namespace() string
+}
+
+var registry = struct {
+	mu    sync.Mutex
+	codes map[string]map[uint32]bool
+}{
+	codes: map[string]map[uint32]bool{},
+}
+
+// Register creates a new error registered under namespace and code, and
+// returns it as a phayes Error that can be wrapped, given values, and
+// inspected with Code, Namespace, and ABCIInfo just like any other error in
+// this package.
+//
+// Code 0 is reserved to mean "no error" and cannot be registered. Registering
+// the same (namespace, code) pair twice panics, so collisions are caught at
+// init time rather than silently mapping two unrelated errors onto the same
+// wire code.
+func Register(namespace string, code uint32, description string) Error {
+	if code == 0 {
+		panic(`errors: cannot register code 0, it is reserved to mean "no error"`)
+	}
+
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	if registry.codes[namespace] == nil {
+		registry.codes[namespace] = map[uint32]bool{}
+	}
+	if registry.codes[namespace][code] {
+		panic(fmt.Sprintf("errors: code %d is already registered in namespace %q", code, namespace))
+	}
+	registry.codes[namespace][code] = true
+
+	return CodedError{
+		DefaultError: DefaultError{
+			err:   errors.New(description),
+			stack: captureStack(),
+		},
+		namespace: namespace,
+		code:      code,
+	}
+}
+
+// findCoded walks err's chain and returns the namespace and code of the
+// innermost Coder found, or ("", 0) if none is found.
+func findCoded(err error) (namespace string, code uint32) {
+	walk(err, func(e error) bool {
+		if c, ok := e.(Coder); ok {
+			namespace = c.Namespace()
+			code = c.Code()
+		}
+		return false // keep walking - we want the innermost match
+	})
+	return namespace, code
+}
+
+// Code returns the code of the innermost coded error in err's chain, or 0
+// ("no error") if err's chain contains none.
+func Code(err error) uint32 {
+	_, code := findCoded(err)
+	return code
+}
+
+// Namespace returns the namespace of the innermost coded error in err's
+// chain, or "" if err's chain contains none.
+func Namespace(err error) string {
+	namespace, _ := findCoded(err)
+	return namespace
+}
+
+// ABCIInfo extracts the namespace and code of the innermost coded error in
+// err's chain, plus a log string describing err, mirroring the fields an
+// ABCI response or gRPC status needs. When debug is true, log is the full
+// %+v chain, including stack traces; otherwise it's just the compact
+// Error() string, since debug output may include internal details that
+// shouldn't reach untrusted callers.
+func ABCIInfo(err error, debug bool) (namespace string, code uint32, log string) {
+	namespace, code = findCoded(err)
+	if debug {
+		log = fmt.Sprintf("%+v", err)
+	} else {
+		log = err.Error()
+	}
+	return namespace, code, log
+}
@@ -0,0 +1,137 @@
+package errors_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/phayes/errors"
+)
+
+var (
+	ErrNotFound = errors.Register("testmod", 1, "not found")
+	ErrInvalid  = errors.Register("testmod", 2, "invalid input")
+	ErrOtherNS  = errors.Register("othermod", 1, "also coded")
+)
+
+func TestRegisterPanicsOnDuplicate(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Register did not panic on a duplicate (namespace, code) pair")
+		}
+	}()
+	errors.Register("testmod", 1, "duplicate")
+}
+
+func TestRegisterPanicsOnCodeZero(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Register did not panic when registering reserved code 0")
+		}
+	}()
+	errors.Register("testmod", 0, "no error")
+}
+
+func TestCodeAndNamespace(t *testing.T) {
+	if errors.Code(ErrNotFound) != 1 {
+		t.Error("Code did not return the registered code")
+		return
+	}
+	if errors.Namespace(ErrNotFound) != "testmod" {
+		t.Error("Namespace did not return the registered namespace")
+		return
+	}
+	if errors.Code(ErrOtherNS) != 1 || errors.Namespace(ErrOtherNS) != "othermod" {
+		t.Error("distinct namespaces with the same code were conflated")
+		return
+	}
+	if errors.Code(ErrStd) != 0 {
+		t.Error("Code should return 0 for an error with no registered code")
+		return
+	}
+}
+
+func TestCodeThroughWrapChain(t *testing.T) {
+	err := errors.Wraps(ErrNotFound, "user lookup failed")
+
+	if errors.Code(err) != 1 {
+		t.Error("Code did not find the coded error through a wrap chain")
+		return
+	}
+	if errors.Namespace(err) != "testmod" {
+		t.Error("Namespace did not find the coded error through a wrap chain")
+		return
+	}
+	if !errors.IsA(err, ErrNotFound) {
+		t.Error("IsA did not recognize a wrap chain as containing its registered coded error")
+		return
+	}
+}
+
+func TestCodeFindsInnermost(t *testing.T) {
+	// ErrInvalid wraps ErrNotFound: the innermost coded error is ErrNotFound.
+	err := errors.Wrap(ErrNotFound, ErrInvalid)
+
+	if errors.Code(err) != 1 {
+		t.Error("Code did not return the innermost coded error's code")
+		return
+	}
+	if errors.Namespace(err) != "testmod" {
+		t.Error("Namespace did not return the innermost coded error's namespace")
+		return
+	}
+}
+
+func TestStackUpdatesOnCodedErrorWrap(t *testing.T) {
+	base := errors.Register("repro", 99, "repro base")
+	baseFrame := base.(errors.StackTracer).StackTrace()[0]
+
+	wrapped := errors.Append(base, errors.New("context"))
+	wrappedFrame := wrapped.(errors.StackTracer).StackTrace()[0]
+
+	if wrappedFrame.Line == baseFrame.Line {
+		t.Error("Append should replace a CodedError's stack trace with its own call site, not keep Register's")
+		return
+	}
+	if _, ok := wrapped.(errors.CodedError); !ok {
+		t.Error("Append should preserve the CodedError type when re-stacking")
+		return
+	}
+}
+
+func TestFormatPreservesCodedErrorInChain(t *testing.T) {
+	base := errors.Register("test", 7, "coded base")
+	err := errors.Wrap(base, errors.New("outer"))
+
+	verbose := fmt.Sprintf("%+v", err)
+	if !strings.Contains(verbose, "coded base") {
+		t.Errorf("expected %%+v to include the CodedError's message, got %s", verbose)
+		return
+	}
+
+	baseFrame := base.(errors.StackTracer).StackTrace()[0]
+	if !strings.Contains(verbose, baseFrame.File) {
+		t.Errorf("expected %%+v to include the CodedError's stack frame, got %s", verbose)
+		return
+	}
+}
+
+func TestABCIInfo(t *testing.T) {
+	err := errors.Wraps(ErrNotFound, "user lookup failed")
+
+	namespace, code, log := errors.ABCIInfo(err, false)
+	if namespace != "testmod" || code != 1 {
+		t.Error("ABCIInfo returned the wrong namespace or code")
+		return
+	}
+	if log != err.Error() {
+		t.Error("ABCIInfo with debug=false should use the compact Error() string")
+		return
+	}
+
+	_, _, debugLog := errors.ABCIInfo(err, true)
+	if debugLog == log {
+		t.Error("ABCIInfo with debug=true should differ from the compact output")
+		return
+	}
+}
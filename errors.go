@@ -20,27 +20,45 @@ type Error interface {
 	// This method is called to check two errors for equality
 	Base() error
 
+	// WithValue attaches a key/value pair to the error and returns a copy
+	// carrying it. Look it back up with the package-level Value function.
+	WithValue(key interface{}, value interface{}) Error
+
 	// Implements the built-in error interface.
 	Error() string
 }
 
 // DefaultError is the default implementation of Error interface
 type DefaultError struct {
-	err   error
-	inner error
+	err    error
+	inner  error
+	stack  *stack
+	values *valueNode
 }
 
-// Message returns a string with error information, excluding inner errors
+// Message returns a string with error information, excluding inner errors.
+// A DefaultError built with no message of its own - such as one created by
+// withValue to attach a value to a plain error without altering its text -
+// returns "".
 func (e DefaultError) Message() string {
+	if e.err == nil {
+		return ""
+	}
 	return e.err.Error()
 }
 
 // Error returns a string with all available error information, including inner
 // errors that are wrapped by this errors.
 func (e DefaultError) Error() string {
-	if e.inner != nil {
+	switch {
+	case e.err == nil:
+		if e.inner != nil {
+			return e.inner.Error()
+		}
+		return ""
+	case e.inner != nil:
 		return e.Message() + ". " + e.inner.Error()
-	} else {
+	default:
 		return e.Message()
 	}
 }
@@ -50,6 +68,20 @@ func (e DefaultError) Inner() error {
 	return e.inner
 }
 
+// Unwrap returns the inner error, allowing DefaultError to participate in
+// the standard library's errors.Is, errors.As and errors.Unwrap traversal,
+// including chains that pass through a stdlib fmt.Errorf("%w", ...) link.
+func (e DefaultError) Unwrap() error {
+	return e.inner
+}
+
+// Is reports whether target is the same error as e, using the same Base()
+// comparison as Equal. This lets the standard library's errors.Is walk a
+// phayes chain and stop as soon as it reaches an equivalent error.
+func (e DefaultError) Is(target error) bool {
+	return Equal(e, target)
+}
+
 // Base gets the base error that forms the basis of the DefaultError - returns a copy of itself without inners
 func (e DefaultError) Base() error {
 	return e.err
@@ -61,9 +93,25 @@ func (e DefaultError) Wrap(err error) Error {
 	return e
 }
 
+// withStack returns a copy of e with its stack trace replaced by s.
+func (e DefaultError) withStack(s *stack) Error {
+	e.stack = s
+	return e
+}
+
 // New create new error from string.
 // It intentionally mirrors the standard "errors" module so as to be a drop-in replacement
 func New(s string) error {
+	return DefaultError{
+		err:   errors.New(s),
+		stack: captureStack(),
+	}
+}
+
+// NewWithoutStack is the same as New, but never captures a stack trace,
+// regardless of CaptureStack. Use it on hot paths that construct errors
+// frequently and don't need %+v output.
+func NewWithoutStack(s string) error {
 	return DefaultError{
 		err: errors.New(s),
 	}
@@ -73,51 +121,76 @@ func New(s string) error {
 // This is a replacement for fmt.Errorf.
 func Newf(format string, args ...interface{}) error {
 	return DefaultError{
-		err: errors.New(fmt.Sprintf(format, args...)),
+		err:   errors.New(fmt.Sprintf(format, args...)),
+		stack: captureStack(),
 	}
 }
 
 // Append more information to the error. The reverse of Wrap.
 func Append(outerErr error, innerErr error) error {
+	stack := captureStack()
 	if outerError, ok := outerErr.(Error); ok {
-		return outerError.Wrap(innerErr)
+		wrapped := outerError.Wrap(innerErr)
+		if ss, ok := wrapped.(stackSetter); ok {
+			return ss.withStack(stack)
+		}
+		return wrapped
 	}
 	return DefaultError{
 		err:   outerErr,
 		inner: innerErr,
+		stack: stack,
 	}
 }
 
 // Appends more information to the error using a string. The reverse of Wraps.
 func Appends(outerErr error, inner string) error {
+	stack := captureStack()
 	if outerError, ok := outerErr.(Error); ok {
-		return outerError.Wrap(New(inner))
+		wrapped := outerError.Wrap(New(inner))
+		if ss, ok := wrapped.(stackSetter); ok {
+			return ss.withStack(stack)
+		}
+		return wrapped
 	}
 	return DefaultError{
 		err:   outerErr,
 		inner: errors.New(inner),
+		stack: stack,
 	}
 }
 
 // Appendf appends more information to the error using formatting. The reverse of Wrapf.
 func Appendf(outerErr error, format string, args ...interface{}) error {
+	stack := captureStack()
 	if outerError, ok := outerErr.(Error); ok {
-		return outerError.Wrap(Newf(format, args...))
+		wrapped := outerError.Wrap(Newf(format, args...))
+		if ss, ok := wrapped.(stackSetter); ok {
+			return ss.withStack(stack)
+		}
+		return wrapped
 	}
 	return DefaultError{
 		err:   outerErr,
 		inner: Newf(format, args...),
+		stack: stack,
 	}
 }
 
 // Wrap the first error in the second error. Reverse of Append
 func Wrap(innerErr error, outerErr error) error {
+	stack := captureStack()
 	if outerError, ok := outerErr.(Error); ok {
-		return outerError.Wrap(innerErr)
+		wrapped := outerError.Wrap(innerErr)
+		if ss, ok := wrapped.(stackSetter); ok {
+			return ss.withStack(stack)
+		}
+		return wrapped
 	}
 	return DefaultError{
 		err:   outerErr,
 		inner: innerErr,
+		stack: stack,
 	}
 }
 
@@ -126,6 +199,7 @@ func Wraps(err error, outer string) error {
 	return DefaultError{
 		err:   errors.New(outer),
 		inner: err,
+		stack: captureStack(),
 	}
 }
 
@@ -134,6 +208,7 @@ func Wrapf(err error, format string, args ...interface{}) error {
 	return DefaultError{
 		err:   errors.New(fmt.Sprintf(format, args...)),
 		inner: err,
+		stack: captureStack(),
 	}
 }
 
@@ -161,33 +236,91 @@ func Equal(e1 error, e2 error) bool {
 	}
 }
 
-// IsA checks if two errors are the same or if the first contains the second
-// This will recursively check their inner components to see if one is an instance of the other
-func IsA(outerErr error, innerErr error) bool {
-	if Equal(outerErr, innerErr) {
-		return true
+// unwrapChildren returns the direct children of err for the purposes of tree
+// traversal: the errors produced by an Unwrap() []error method (as used by
+// Join), else the single error produced by an Unwrap() error method (as used
+// by DefaultError and stdlib fmt.Errorf("%w", ...)), else the Inner() of a
+// phayes Error, for implementations that predate Unwrap.
+func unwrapChildren(err error) []error {
+	switch x := err.(type) {
+	case interface{ Unwrap() []error }:
+		return x.Unwrap()
+	case interface{ Unwrap() error }:
+		if inner := x.Unwrap(); inner != nil {
+			return []error{inner}
+		}
+		return nil
+	case Error:
+		if inner := x.Inner(); inner != nil {
+			return []error{inner}
+		}
+		return nil
 	}
+	return nil
+}
 
-	// Recursively check to see if the inner is contained in the outer
-	if outerError, ok := outerErr.(Error); ok {
-		if outerInner := outerError.Inner(); outerInner != nil {
-			return IsA(outerInner, innerErr)
+// walk visits err and then each of its children, pre-order depth-first, left
+// to right, stopping as soon as visit returns true.
+func walk(err error, visit func(error) bool) bool {
+	if err == nil {
+		return false
+	}
+	if visit(err) {
+		return true
+	}
+	for _, child := range unwrapChildren(err) {
+		if walk(child, visit) {
+			return true
 		}
 	}
-
-	// No match
 	return false
 }
 
-// Cause returns the root cause of the given error. If err does not implement phayes.Error, it returns err itself.
+// IsA checks if two errors are the same or if the first contains the second.
+// It performs a pre-order depth-first traversal of outerErr's error tree,
+// following both single-error Unwrap() chains (DefaultError and stdlib
+// fmt.Errorf("%w", ...) links) and the multi-error Unwrap() []error chains
+// produced by Join, so a wrapper from another package or a joined error in
+// the middle of a chain doesn't break the check.
+func IsA(outerErr error, innerErr error) bool {
+	return walk(outerErr, func(e error) bool {
+		return Equal(e, innerErr)
+	})
+}
+
+// Is is an alias for IsA, provided so code that already imports the standard
+// "errors" package under this name keeps working unchanged.
+func Is(outerErr error, innerErr error) bool {
+	return IsA(outerErr, innerErr)
+}
+
+// As finds the first error in err's chain that matches target, and if one is
+// found, sets target to that error value and returns true. It is a thin
+// wrapper around the standard library's errors.As; it works across phayes
+// chains because DefaultError implements Unwrap().
+func As(err error, target interface{}) bool {
+	return errors.As(err, target)
+}
+
+// Unwrap returns the result of calling the Unwrap method on err, if it
+// implements one. It is a thin wrapper around the standard library's
+// errors.Unwrap, provided so callers don't need to import both packages.
+func Unwrap(err error) error {
+	return errors.Unwrap(err)
+}
+
+// Cause returns the root cause of the given error - the innermost error
+// reached by repeatedly unwrapping err. If err wraps multiple errors (as
+// produced by Join), Cause follows the first one to its leaf. If err does
+// not implement phayes.Error and has no further children, it returns err
+// itself.
 func Cause(err error) error {
-	outerError, ok := err.(Error)
-	if !ok {
+	children := unwrapChildren(err)
+	if len(children) == 0 {
+		if outerError, ok := err.(Error); ok {
+			return outerError.Base()
+		}
 		return err
 	}
-
-	if outerError.Inner() == nil {
-		return outerError.Base()
-	}
-	return Cause(outerError.Inner())
+	return Cause(children[0])
 }
@@ -2,6 +2,7 @@ package errors_test
 
 import (
 	stderrors "errors"
+	"fmt"
 	"testing"
 
 	"github.com/phayes/errors"
@@ -154,6 +155,64 @@ func TestCause(t *testing.T) {
 
 }
 
+func TestUnwrap(t *testing.T) {
+	err := FooWrappingBar()
+	if stderrors.Unwrap(err) != ErrBar {
+		t.Error("stdlib errors.Unwrap did not return the inner error")
+		return
+	}
+	if errors.Unwrap(err) != ErrBar {
+		t.Error("errors.Unwrap did not return the inner error")
+		return
+	}
+	if stderrors.Unwrap(ErrStd) != nil {
+		t.Error("stdlib errors.Unwrap should return nil for an error with no inner")
+		return
+	}
+}
+
+func TestStdlibInteropAcrossFmtErrorf(t *testing.T) {
+	// A fmt.Errorf("%w", ...) link in the middle of a chain should not break IsA.
+	err := fmt.Errorf("middleman: %w", FooWrappingBar())
+
+	if !errors.IsA(err, ErrFoo) {
+		t.Error("IsA did not traverse a fmt.Errorf(\"%w\", ...) link to find ErrFoo")
+		return
+	}
+	if !errors.IsA(err, ErrBar) {
+		t.Error("IsA did not traverse a fmt.Errorf(\"%w\", ...) link to find ErrBar")
+		return
+	}
+	if !errors.Is(err, ErrBar) {
+		t.Error("Is did not traverse a fmt.Errorf(\"%w\", ...) link to find ErrBar")
+		return
+	}
+	if !stderrors.Is(err, ErrBar) {
+		t.Error("stdlib errors.Is did not traverse into a phayes chain to find ErrBar")
+		return
+	}
+}
+
+func TestAs(t *testing.T) {
+	err := fmt.Errorf("wrapping: %w", errors.Wrap(myError{}, ErrFoo))
+
+	var target myError
+	if !errors.As(err, &target) {
+		t.Error("As did not find myError in the chain")
+		return
+	}
+	if target.Error() != "my typed error" {
+		t.Error("As populated target with the wrong error")
+		return
+	}
+}
+
+type myError struct{}
+
+func (myError) Error() string {
+	return "my typed error"
+}
+
 func StdErrorWrappingFoo() error {
 	return errors.Wrap(ErrFoo, ErrStd)
 }
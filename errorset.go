@@ -5,10 +5,21 @@ import (
 	"sync"
 )
 
-// ErrorSet is a set of errors that can be collected together in a non-heirarchical manner.
+// entry is one (key, error) pair in an ErrorSet, kept in the order it was
+// added.
+type entry struct {
+	key string
+	err error
+}
+
+// ErrorSet is a set of errors that can be collected together in a
+// non-heirarchical manner, keyed by a string. Add replaces any error
+// previously added under the same key; Append accumulates multiple errors
+// under the same key instead. Error(), Range, and Unwrap always visit
+// entries in the order they were added, so output is deterministic.
 type ErrorSet struct {
-	mu  sync.Mutex
-	set map[string]error
+	mu      sync.Mutex
+	entries []entry
 }
 
 // NewErrorSet creates a new empty ErrorSet
@@ -21,56 +32,112 @@ func (e *ErrorSet) Error() string {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
-	if e == nil || e.set == nil || len(e.set) == 0 {
+	if e == nil || len(e.entries) == 0 {
 		return ""
 	}
-	output := ""
-	for str, err := range e.set {
-		output += str + ": " + err.Error() + ". "
+
+	var b strings.Builder
+	for i, en := range e.entries {
+		if i > 0 {
+			b.WriteString(". ")
+		}
+		b.WriteString(en.key)
+		b.WriteString(": ")
+		b.WriteString(en.err.Error())
 	}
-	output = strings.TrimRight(output, ". ")
-	return output
+	return b.String()
 }
 
-// Add an error to the error set
+// Add an error to the error set under key, replacing any error already
+// added under that key. Use Append to accumulate multiple errors per key.
 func (e *ErrorSet) Add(key string, err error) {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
-	if e.set == nil {
-		e.set = map[string]error{}
+	for i, en := range e.entries {
+		if en.key == key {
+			e.entries[i].err = err
+			return
+		}
 	}
+	e.entries = append(e.entries, entry{key: key, err: err})
+}
 
-	e.set[key] = err
+// Append adds an error to the error set under key, without replacing any
+// error already added under that key. The set then holds multiple errors
+// for that key, all visited in insertion order by Error, Range, and Unwrap.
+func (e *ErrorSet) Append(key string, err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.entries = append(e.entries, entry{key: key, err: err})
 }
 
-// Get an error from the error set
+// Get an error from the error set. If key has more than one error, because
+// it was built up with Append, Get returns the first one added; use Range
+// to see them all.
 func (e *ErrorSet) Get(key string) error {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
-	if e.set == nil {
-		return nil
+	for _, en := range e.entries {
+		if en.key == key {
+			return en.err
+		}
 	}
-	return e.set[key]
+	return nil
 }
 
-// GetAll Gets all errors from the error set
+// GetAll Gets all errors from the error set. If a key has more than one
+// error, because it was built up with Append, only the first is included;
+// use Range to see them all.
 func (e *ErrorSet) GetAll() map[string]error {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
-	set := map[string]error{}
-
-	if e.set == nil {
-		return set
+	all := map[string]error{}
+	for _, en := range e.entries {
+		if _, ok := all[en.key]; !ok {
+			all[en.key] = en.err
+		}
 	}
+	return all
+}
+
+// Range calls f for every (key, error) pair in the set, in insertion order,
+// while holding the set's lock. If f returns false, Range stops early.
+func (e *ErrorSet) Range(f func(key string, err error) bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
 
-	for k, v := range e.set {
-		set[k] = v
+	for _, en := range e.entries {
+		if !f(en.key, en.err) {
+			return
+		}
 	}
+}
 
-	return set
+// Len returns the number of (key, error) entries in the set, counting every
+// error added with Append separately.
+func (e *ErrorSet) Len() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return len(e.entries)
+}
+
+// Delete removes every error added under key, however it was added.
+func (e *ErrorSet) Delete(key string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	kept := e.entries[:0]
+	for _, en := range e.entries {
+		if en.key != key {
+			kept = append(kept, en)
+		}
+	}
+	e.entries = kept
 }
 
 // HasErrors returns true if the ErrorSet contains an error
@@ -78,9 +145,22 @@ func (e *ErrorSet) HasErrors() bool {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
-	if e.set == nil || len(e.set) == 0 {
-		return false
-	}
+	return len(e.entries) > 0
+}
+
+// Unwrap returns every error in the set, in insertion order, so an
+// *ErrorSet plugs directly into IsA/Is/As/Cause tree traversal - for
+// example, IsA(set, ErrFoo) is true if any member of the set matches ErrFoo.
+func (e *ErrorSet) Unwrap() []error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
 
-	return true
+	if len(e.entries) == 0 {
+		return nil
+	}
+	errs := make([]error, len(e.entries))
+	for i, en := range e.entries {
+		errs[i] = en.err
+	}
+	return errs
 }
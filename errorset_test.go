@@ -1,6 +1,8 @@
 package errors_test
 
 import (
+	"fmt"
+	"sync"
 	"testing"
 
 	"github.com/phayes/errors"
@@ -55,8 +57,118 @@ func TestErrorSet(t *testing.T) {
 		return
 	}
 
-	if errset.Error() != "foo: Foo. bar: Bar" && errset.Error() != "bar: Bar. foo: Foo" {
+	if errset.Error() != "foo: Foo. bar: Bar" {
 		t.Error("Wrong errset.Error() output")
 		return
 	}
 }
+
+func TestErrorSetOrdering(t *testing.T) {
+	errset := errors.NewErrorSet()
+
+	errset.Add("z", errors.New("Z"))
+	errset.Add("a", errors.New("A"))
+	errset.Add("m", errors.New("M"))
+
+	if errset.Error() != "z: Z. a: A. m: M" {
+		t.Error("ErrorSet.Error() should follow insertion order, not key order")
+		return
+	}
+}
+
+func TestErrorSetAppend(t *testing.T) {
+	errset := errors.NewErrorSet()
+
+	errset.Append("field", errors.New("required"))
+	errset.Append("field", errors.New("too long"))
+
+	if errset.Len() != 2 {
+		t.Error("Append should accumulate multiple errors under the same key")
+		return
+	}
+	if errset.Get("field").Error() != "required" {
+		t.Error("Get should return the first error added under a key")
+		return
+	}
+
+	var got []string
+	errset.Range(func(key string, err error) bool {
+		got = append(got, key+": "+err.Error())
+		return true
+	})
+	if len(got) != 2 || got[0] != "field: required" || got[1] != "field: too long" {
+		t.Error("Range should visit every entry, in insertion order")
+		return
+	}
+}
+
+func TestErrorSetDelete(t *testing.T) {
+	errset := errors.NewErrorSet()
+
+	errset.Add("foo", ErrFoo2)
+	errset.Add("bar", ErrBar2)
+	errset.Delete("foo")
+
+	if errset.Len() != 1 {
+		t.Error("Delete should remove the entry for the given key")
+		return
+	}
+	if errset.Get("foo") != nil {
+		t.Error("Get should return nil for a deleted key")
+		return
+	}
+	if errset.Get("bar") != ErrBar2 {
+		t.Error("Delete should not affect other keys")
+		return
+	}
+}
+
+func TestErrorSetConcurrentAccess(t *testing.T) {
+	errset := errors.NewErrorSet()
+
+	const goroutines = 50
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			key := fmt.Sprintf("key%d", i%5)
+			errset.Add(key, errors.New("add"))
+			errset.Append(key, errors.New("append"))
+			errset.Get(key)
+			errset.GetAll()
+			errset.Len()
+			errset.HasErrors()
+			errset.Range(func(key string, err error) bool { return true })
+		}(i)
+	}
+	wg.Wait()
+
+	if !errset.HasErrors() {
+		t.Error("expected concurrent Add/Append to leave entries in the set")
+		return
+	}
+	if errset.Len() == 0 {
+		t.Error("expected concurrent Append calls to have accumulated entries")
+		return
+	}
+}
+
+func TestErrorSetUnwrap(t *testing.T) {
+	errset := errors.NewErrorSet()
+	errset.Add("foo", ErrFoo2)
+	errset.Add("bar", ErrBar2)
+
+	if !errors.IsA(errset, ErrFoo2) {
+		t.Error("IsA should find ErrFoo2 through an ErrorSet's Unwrap")
+		return
+	}
+	if !errors.IsA(errset, ErrBar2) {
+		t.Error("IsA should find ErrBar2 through an ErrorSet's Unwrap")
+		return
+	}
+	if errors.IsA(errset, ErrStd) {
+		t.Error("IsA should not find an error that isn't in the set")
+		return
+	}
+}
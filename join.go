@@ -0,0 +1,52 @@
+package errors
+
+// joinError is the error type returned by Join. It implements Unwrap()
+// []error, mirroring the standard library's Go 1.20 errors.Join, so that an
+// error is treated as a tree of causes rather than a linear chain.
+type joinError struct {
+	errs []error
+}
+
+// Join returns an error that wraps the given errors. Any nil error values
+// are discarded. Join returns nil if every value in errs is nil.
+//
+// The returned error implements Unwrap() []error, and so participates in
+// IsA, Cause, and errors.Is/As tree traversal: IsA(Join(a, b), target) is
+// true if either a or b matches target.
+func Join(errs ...error) error {
+	n := 0
+	for _, err := range errs {
+		if err != nil {
+			n++
+		}
+	}
+	if n == 0 {
+		return nil
+	}
+
+	e := &joinError{errs: make([]error, 0, n)}
+	for _, err := range errs {
+		if err != nil {
+			e.errs = append(e.errs, err)
+		}
+	}
+	return e
+}
+
+// Error returns the error messages of every joined error, one per line.
+func (e *joinError) Error() string {
+	msg := ""
+	for i, err := range e.errs {
+		if i > 0 {
+			msg += "\n"
+		}
+		msg += err.Error()
+	}
+	return msg
+}
+
+// Unwrap returns the joined errors, allowing standard library and phayes
+// tree traversal to visit every leaf.
+func (e *joinError) Unwrap() []error {
+	return e.errs
+}
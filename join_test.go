@@ -0,0 +1,55 @@
+package errors_test
+
+import (
+	"testing"
+
+	"github.com/phayes/errors"
+)
+
+func TestJoin(t *testing.T) {
+	if errors.Join() != nil {
+		t.Error("Join of no errors should be nil")
+		return
+	}
+	if errors.Join(nil, nil) != nil {
+		t.Error("Join of only nils should be nil")
+		return
+	}
+
+	joined := errors.Join(ErrFoo, ErrBar, nil, ErrStd)
+	if joined.Error() != "Fooey\nBarf\nThis is a stanard error from the standard library" {
+		t.Error("Wrong Error() output for Join")
+		return
+	}
+
+	if !errors.IsA(joined, ErrFoo) {
+		t.Error("IsA did not find ErrFoo in a joined error")
+		return
+	}
+	if !errors.IsA(joined, ErrBar) {
+		t.Error("IsA did not find ErrBar in a joined error")
+		return
+	}
+	if !errors.IsA(joined, ErrStd) {
+		t.Error("IsA did not find ErrStd in a joined error")
+		return
+	}
+	if errors.IsA(joined, ErrStd2) {
+		t.Error("IsA incorrectly found ErrStd2 in a joined error")
+		return
+	}
+}
+
+func TestJoinNested(t *testing.T) {
+	// A joined branch can itself be a phayes wrap chain.
+	joined := errors.Join(FooWrappingBar(), ErrStd2)
+
+	if !errors.IsA(joined, ErrBar) {
+		t.Error("IsA did not traverse into a wrapped branch of a joined error to find ErrBar")
+		return
+	}
+	if !errors.Equal(errors.Cause(joined), ErrBar) {
+		t.Error("Cause of a joined error should follow the first branch to its leaf")
+		return
+	}
+}
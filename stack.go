@@ -0,0 +1,133 @@
+package errors
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// CaptureStack controls whether New, Newf, Wrap, Wraps, Wrapf, Append,
+// Appends, and Appendf record a stack trace at the point they're called.
+// Capturing a stack costs roughly a microsecond; set this to false on hot
+// paths that construct errors frequently and don't need %+v output. Use
+// NewWithoutStack to opt a single call out without touching this toggle.
+var CaptureStack = true
+
+// maxStackDepth bounds how many frames are recorded per captured stack.
+const maxStackDepth = 32
+
+// StackTracer may be implemented by errors that capture a stack trace at the
+// point they were created or wrapped.
+type StackTracer interface {
+	StackTrace() []runtime.Frame
+}
+
+// stackSetter is implemented by Error types that can have their captured
+// stack trace replaced, such as DefaultError and CodedError. Append, Wrap,
+// Appends, and Appendf use it to attach the stack they captured at their own
+// call site after delegating to outerErr.Wrap - checking for this interface
+// rather than asserting the concrete DefaultError type means any Error that
+// embeds DefaultError keeps its own type after being re-stacked.
+type stackSetter interface {
+	withStack(s *stack) Error
+}
+
+// stack holds the raw program counters for a captured stack trace. It's
+// stored behind a pointer, rather than as a bare []uintptr field on
+// DefaultError, so that DefaultError itself remains comparable with == -
+// Equal relies on that for its initial e1 == e2 check.
+type stack []uintptr
+
+// captureStack records the call stack of its caller's caller, skipping over
+// captureStack itself and the phayes function that invoked it. It must be
+// called directly from New, Newf, Wrap, Wraps, Wrapf, Append, Appends,
+// Appendf, or Register so that the recorded frames start at the caller of those
+// functions, not somewhere inside this package.
+func captureStack() *stack {
+	if !CaptureStack {
+		return nil
+	}
+	var pcs [maxStackDepth]uintptr
+	n := runtime.Callers(3, pcs[:])
+	s := stack(pcs[:n])
+	return &s
+}
+
+// StackTrace returns the stack frames captured when this error was created
+// or wrapped, most recent call first. It returns nil if no stack was
+// captured, either because CaptureStack was false or the error was built
+// with NewWithoutStack.
+func (e DefaultError) StackTrace() []runtime.Frame {
+	if e.stack == nil || len(*e.stack) == 0 {
+		return nil
+	}
+	frames := runtime.CallersFrames(*e.stack)
+	trace := make([]runtime.Frame, 0, len(*e.stack))
+	for {
+		frame, more := frames.Next()
+		trace = append(trace, frame)
+		if !more {
+			break
+		}
+	}
+	return trace
+}
+
+// Format implements fmt.Formatter. %s and %v print the same compact,
+// ". "-joined message as Error(). %+v additionally walks the wrap chain,
+// printing the file:line of each wrap site underneath its message, mirroring
+// the well-known pkg/errors idiom.
+func (e DefaultError) Format(f fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if f.Flag('+') {
+			fmt.Fprint(f, e.verboseString())
+			return
+		}
+		fmt.Fprint(f, e.Error())
+	case 's':
+		fmt.Fprint(f, e.Error())
+	case 'q':
+		fmt.Fprintf(f, "%q", e.Error())
+	}
+}
+
+// verboseString renders the wrap chain one link per line, with the
+// file:line of each link's stack trace indented underneath it. Each link is
+// handled through the Error and StackTracer interfaces rather than asserted
+// to the concrete DefaultError type - the same reasoning as stackSetter -
+// so a type that embeds DefaultError, such as CodedError, keeps contributing
+// its own message and stack frames instead of being mistaken for the end of
+// the chain. A link with no message of its own, such as the message-less
+// wrapper withValue conses onto a plain error, contributes nothing rather
+// than a blank line.
+func (e DefaultError) verboseString() string {
+	var b strings.Builder
+	var cur error = e
+	first := true
+	for cur != nil {
+		ee, ok := cur.(Error)
+		if !ok {
+			if !first {
+				b.WriteString("\n")
+			}
+			b.WriteString(cur.Error())
+			break
+		}
+		if msg := ee.Message(); msg != "" {
+			if !first {
+				b.WriteString("\n")
+			}
+			b.WriteString(msg)
+			first = false
+		}
+		if st, ok := cur.(StackTracer); ok {
+			for _, frame := range st.StackTrace() {
+				fmt.Fprintf(&b, "\n\t%s:%d", frame.File, frame.Line)
+				first = false
+			}
+		}
+		cur = ee.Inner()
+	}
+	return b.String()
+}
@@ -0,0 +1,112 @@
+package errors_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/phayes/errors"
+)
+
+func newHere() error {
+	return errors.New("stack test base")
+}
+
+func wrapHere(err error) error {
+	return errors.Wraps(err, "stack test wrap")
+}
+
+func TestStackTraceCapturesCallSite(t *testing.T) {
+	err := newHere()
+
+	tracer, ok := err.(errors.StackTracer)
+	if !ok {
+		t.Error("DefaultError does not implement StackTracer")
+		return
+	}
+
+	frames := tracer.StackTrace()
+	if len(frames) == 0 {
+		t.Error("expected at least one captured frame")
+		return
+	}
+
+	if !strings.HasSuffix(frames[0].File, "stack_test.go") {
+		t.Errorf("expected the first frame to point at the caller of New, got %s:%d", frames[0].File, frames[0].Line)
+		return
+	}
+	if strings.Contains(frames[0].Function, "phayes/errors.New") {
+		t.Error("expected the first frame to be the caller of New, not New itself")
+		return
+	}
+}
+
+func TestStackTraceCapturesWrapSite(t *testing.T) {
+	err := wrapHere(errors.New("inner"))
+
+	tracer, ok := err.(errors.StackTracer)
+	if !ok {
+		t.Error("DefaultError does not implement StackTracer")
+		return
+	}
+
+	frames := tracer.StackTrace()
+	if len(frames) == 0 {
+		t.Error("expected at least one captured frame")
+		return
+	}
+	if !strings.HasSuffix(frames[0].File, "stack_test.go") {
+		t.Errorf("expected the first frame to point at the caller of Wraps, got %s:%d", frames[0].File, frames[0].Line)
+		return
+	}
+}
+
+func TestNewWithoutStack(t *testing.T) {
+	err := errors.NewWithoutStack("no stack here")
+
+	tracer, ok := err.(errors.StackTracer)
+	if !ok {
+		t.Error("DefaultError does not implement StackTracer")
+		return
+	}
+	if len(tracer.StackTrace()) != 0 {
+		t.Error("NewWithoutStack should not capture a stack trace")
+		return
+	}
+}
+
+func TestCaptureStackToggle(t *testing.T) {
+	errors.CaptureStack = false
+	defer func() { errors.CaptureStack = true }()
+
+	err := errors.New("toggled off")
+	tracer := err.(errors.StackTracer)
+	if len(tracer.StackTrace()) != 0 {
+		t.Error("expected no stack trace captured while CaptureStack is false")
+		return
+	}
+}
+
+func TestFormatVerbs(t *testing.T) {
+	err := errors.Wraps(errors.New("inner"), "outer")
+
+	compact := fmt.Sprintf("%s", err)
+	if compact != "outer. inner" {
+		t.Errorf("wrong %%s output: %s", compact)
+		return
+	}
+	if fmt.Sprintf("%v", err) != compact {
+		t.Error("plain v output should match the s output")
+		return
+	}
+
+	verbose := fmt.Sprintf("%+v", err)
+	if !strings.Contains(verbose, "outer") || !strings.Contains(verbose, "inner") {
+		t.Errorf("expected %%+v to contain both messages, got %s", verbose)
+		return
+	}
+	if !strings.Contains(verbose, "stack_test.go:") {
+		t.Errorf("expected %%+v to contain a file:line, got %s", verbose)
+		return
+	}
+}
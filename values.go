@@ -0,0 +1,104 @@
+package errors
+
+// valueNode is one link in the immutable key/value list attached to a
+// DefaultError by WithValue. Each call conses a new node onto the front, so
+// a later WithValue call for the same key shadows an earlier one at that
+// node.
+type valueNode struct {
+	key   interface{}
+	value interface{}
+	next  *valueNode
+}
+
+// WithValue returns a copy of e with value attached under key. Look it back
+// up anywhere in the wrap chain with the package-level Value function.
+func (e DefaultError) WithValue(key interface{}, value interface{}) Error {
+	e.values = &valueNode{key: key, value: value, next: e.values}
+	return e
+}
+
+// valueAt looks up key among the values attached directly to e, without
+// descending into e's wrap chain.
+func (e DefaultError) valueAt(key interface{}) (interface{}, bool) {
+	for n := e.values; n != nil; n = n.next {
+		if n.key == key {
+			return n.value, true
+		}
+	}
+	return nil, false
+}
+
+// valuer is implemented by errors that can be queried for a key/value pair
+// attached by WithValue.
+type valuer interface {
+	valueAt(key interface{}) (interface{}, bool)
+}
+
+// Value looks up key in err's wrap chain - including through stdlib Unwrap
+// links - and returns the value attached by the nearest WithValue call, or
+// nil if key was never attached.
+func Value(err error, key interface{}) interface{} {
+	var found interface{}
+	walk(err, func(e error) bool {
+		if v, ok := e.(valuer); ok {
+			if val, ok := v.valueAt(key); ok {
+				found = val
+				return true
+			}
+		}
+		return false
+	})
+	return found
+}
+
+// withValue attaches value under key to err, upgrading err to a DefaultError
+// first if it doesn't already implement Error - the same fallback pattern
+// Append uses. err is kept as the inner error, not the message, so it stays
+// reachable to IsA, Cause, Code, and Value traversal after the upgrade.
+func withValue(err error, key interface{}, value interface{}) error {
+	if e, ok := err.(Error); ok {
+		return e.WithValue(key, value)
+	}
+	return DefaultError{inner: err}.WithValue(key, value)
+}
+
+// contextKey namespaces the keys used by this package's own WithValue
+// helpers so they can't collide with keys callers attach themselves.
+type contextKey int
+
+const (
+	httpStatusKey contextKey = iota
+	userMessageKey
+)
+
+// WithHTTPStatus attaches an HTTP status code to err, so an HTTP handler can
+// map an internal error chain to a response code without parsing the error
+// message.
+func WithHTTPStatus(err error, code int) error {
+	return withValue(err, httpStatusKey, code)
+}
+
+// HTTPStatus returns the HTTP status code attached to err by WithHTTPStatus,
+// or 0 if none was attached.
+func HTTPStatus(err error) int {
+	if code, ok := Value(err, httpStatusKey).(int); ok {
+		return code
+	}
+	return 0
+}
+
+// WithUserMessage attaches a safe, user-facing message to err, distinct from
+// the internal Error() string, so a handler can render it instead of
+// leaking internal details.
+func WithUserMessage(err error, msg string) error {
+	return withValue(err, userMessageKey, msg)
+}
+
+// UserMessage returns the user-facing message attached to err by
+// WithUserMessage, or "" if none was attached.
+func UserMessage(err error) string {
+	if msg, ok := Value(err, userMessageKey).(string); ok {
+		return msg
+	}
+	return ""
+}
@@ -0,0 +1,92 @@
+package errors_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/phayes/errors"
+)
+
+type requestIDKey struct{}
+
+func TestWithValue(t *testing.T) {
+	var err error = errors.New("db error").(errors.Error).WithValue(requestIDKey{}, "req-123")
+	err = errors.Wraps(err, "handler failed")
+
+	if errors.Value(err, requestIDKey{}) != "req-123" {
+		t.Error("Value did not find a value attached further down the chain")
+		return
+	}
+	if errors.Value(err, "missing") != nil {
+		t.Error("Value should return nil for a key that was never attached")
+		return
+	}
+}
+
+func TestWithValueNearestWins(t *testing.T) {
+	inner := errors.New("inner").(errors.Error).WithValue(requestIDKey{}, "inner-id")
+	outer := errors.Wraps(inner, "outer").(errors.Error).WithValue(requestIDKey{}, "outer-id")
+
+	if errors.Value(outer, requestIDKey{}) != "outer-id" {
+		t.Error("Value should return the value attached nearest the top of the chain")
+		return
+	}
+}
+
+func TestHTTPStatus(t *testing.T) {
+	// WithHTTPStatus should work on a plain stdlib error, upgrading it.
+	err := errors.WithHTTPStatus(ErrStd, 404)
+	err = fmt.Errorf("wrapping: %w", err)
+
+	if errors.HTTPStatus(err) != 404 {
+		t.Error("HTTPStatus did not find the status code through a fmt.Errorf wrapper")
+		return
+	}
+	if errors.HTTPStatus(ErrBar) != 0 {
+		t.Error("HTTPStatus should return 0 when no status was attached")
+		return
+	}
+}
+
+func TestWithValuePreservesMultiLayerChain(t *testing.T) {
+	root := errors.New("root")
+	err := fmt.Errorf("layer1: %w", fmt.Errorf("layer2: %w", root))
+	err = errors.WithHTTPStatus(err, 500)
+
+	if !errors.IsA(err, root) {
+		t.Error("WithHTTPStatus must not cut off the stdlib chain it wraps")
+		return
+	}
+	if errors.HTTPStatus(err) != 500 {
+		t.Error("HTTPStatus should still find the status after the chain check")
+		return
+	}
+}
+
+func TestFormatNoBlankLineForValueOnlyWrapper(t *testing.T) {
+	err := errors.WithHTTPStatus(fmt.Errorf("context: %w", errors.New("root cause")), 500)
+
+	verbose := fmt.Sprintf("%+v", err)
+	if strings.HasPrefix(verbose, "\n") {
+		t.Errorf("expected no leading blank line for the message-less wrapper WithHTTPStatus conses on, got %q", verbose)
+		return
+	}
+	if verbose != "context: root cause" {
+		t.Errorf("expected %%+v to equal the compact message since no stack was captured, got %q", verbose)
+		return
+	}
+}
+
+func TestUserMessage(t *testing.T) {
+	err := errors.WithUserMessage(ErrFoo, "Something went wrong, please try again.")
+
+	if errors.UserMessage(err) != "Something went wrong, please try again." {
+		t.Error("UserMessage did not return the attached message")
+		return
+	}
+	if errors.UserMessage(ErrBar) != "" {
+		t.Error("UserMessage should return empty string when no message was attached")
+		return
+	}
+}